@@ -0,0 +1,119 @@
+package drum
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+)
+
+var ErrInvalidVelocity = errors.New("drum: velocity step exceeds 127")
+
+// TrackCodec reads and writes a single track record. The decoder dispatches
+// to a TrackCodec based on a Pattern's HW version string, which lets
+// callers support variant .splice track layouts without forking the
+// package.
+type TrackCodec interface {
+	ReadTrack(er *TrackReader) (Track, error)
+	WriteTrack(w io.Writer, t Track) error
+}
+
+var trackCodecs = map[string]TrackCodec{
+	"": defaultTrackCodec{},
+}
+
+// RegisterTrackCodec associates c with versions whose HW version string
+// starts with versionPrefix. The longest matching prefix wins; an empty
+// prefix registers the fallback used when no other registration matches.
+func RegisterTrackCodec(versionPrefix string, c TrackCodec) {
+	trackCodecs[versionPrefix] = c
+}
+
+func resolveTrackCodec(version string) TrackCodec {
+	var (
+		best      string
+		bestCodec = trackCodecs[""]
+	)
+
+	for prefix, c := range trackCodecs {
+		if prefix == "" || !strings.HasPrefix(version, prefix) {
+			continue
+		}
+		if len(prefix) > len(best) {
+			best, bestCodec = prefix, c
+		}
+	}
+
+	return bestCodec
+}
+
+// defaultTrackCodec reads and writes the track layout understood by every
+// known .splice file: id (LE uint32), instrument length byte, instrument
+// bytes, and 16 step bytes.
+type defaultTrackCodec struct{}
+
+func (defaultTrackCodec) ReadTrack(er *TrackReader) (Track, error) {
+	var id uint32
+	if err := er.Read(binary.LittleEndian, &id); err != nil {
+		// A clean end of the track stream: no bytes of a new track have
+		// been consumed yet, so io.EOF here means "no more tracks".
+		return Track{}, err
+	}
+
+	var length byte
+	er.Read(binary.LittleEndian, &length)
+
+	instrument := make([]byte, length)
+	steps := make([]byte, 16)
+	er.ReadFull(instrument)
+	er.ReadFull(steps)
+
+	if err := er.Err(); err != nil {
+		// The id was already consumed, so running out of data now means
+		// the track was truncated, not that the stream ended cleanly.
+		if err == io.EOF {
+			return Track{}, io.ErrUnexpectedEOF
+		}
+		return Track{}, err
+	}
+
+	return Track{
+		ID:         id,
+		Instrument: string(instrument),
+		Steps:      steps,
+	}, nil
+}
+
+func (defaultTrackCodec) WriteTrack(w io.Writer, t Track) error {
+	if err := binary.Write(w, binary.LittleEndian, t.ID); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, byte(len(t.Instrument))); err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte(t.Instrument)); err != nil {
+		return err
+	}
+	_, err := w.Write(t.Steps)
+	return err
+}
+
+// VelocityTrackCodec is a sample TrackCodec for variant .splice layouts
+// that store a 0-127 velocity per step instead of a plain on/off flag. The
+// wire layout is identical to defaultTrackCodec; only the valid range of
+// each step byte differs. Register it for the HW versions that use it, e.g.
+// RegisterTrackCodec("0.909-vel", VelocityTrackCodec{}).
+type VelocityTrackCodec struct{}
+
+func (VelocityTrackCodec) ReadTrack(er *TrackReader) (Track, error) {
+	return defaultTrackCodec{}.ReadTrack(er)
+}
+
+func (VelocityTrackCodec) WriteTrack(w io.Writer, t Track) error {
+	for _, s := range t.Steps {
+		if s > 127 {
+			return ErrInvalidVelocity
+		}
+	}
+	return defaultTrackCodec{}.WriteTrack(w, t)
+}
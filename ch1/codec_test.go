@@ -0,0 +1,92 @@
+package drum
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestDefaultTrackCodecRoundTrip(t *testing.T) {
+	track := Track{ID: 7, Instrument: "rim", Steps: Steps{1, 0, 1, 0, 1, 0, 1, 0, 1, 0, 1, 0, 1, 0, 1, 0}}
+
+	var buf bytes.Buffer
+	if err := (defaultTrackCodec{}).WriteTrack(&buf, track); err != nil {
+		t.Fatalf("WriteTrack: %v", err)
+	}
+
+	er := TrackReader{&buf, nil}
+	got, err := (defaultTrackCodec{}).ReadTrack(&er)
+	if err != nil {
+		t.Fatalf("ReadTrack: %v", err)
+	}
+	if !reflect.DeepEqual(got, track) {
+		t.Fatalf("ReadTrack() = %+v, want %+v", got, track)
+	}
+}
+
+func TestVelocityTrackCodecRoundTrip(t *testing.T) {
+	track := Track{ID: 1, Instrument: "kick", Steps: Steps{127, 0, 64, 0, 32, 0, 127, 0, 0, 0, 0, 0, 0, 0, 0, 0}}
+
+	var buf bytes.Buffer
+	if err := (VelocityTrackCodec{}).WriteTrack(&buf, track); err != nil {
+		t.Fatalf("WriteTrack: %v", err)
+	}
+
+	er := TrackReader{&buf, nil}
+	got, err := (VelocityTrackCodec{}).ReadTrack(&er)
+	if err != nil {
+		t.Fatalf("ReadTrack: %v", err)
+	}
+	if !reflect.DeepEqual(got, track) {
+		t.Fatalf("ReadTrack() = %+v, want %+v", got, track)
+	}
+}
+
+func TestVelocityTrackCodecRejectsOutOfRangeStep(t *testing.T) {
+	track := Track{ID: 1, Instrument: "kick", Steps: Steps{128, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}}
+
+	err := (VelocityTrackCodec{}).WriteTrack(&bytes.Buffer{}, track)
+	if err != ErrInvalidVelocity {
+		t.Fatalf("WriteTrack() error = %v, want ErrInvalidVelocity", err)
+	}
+}
+
+func TestResolveTrackCodecPicksLongestMatchingPrefix(t *testing.T) {
+	RegisterTrackCodec("0.909-vel", VelocityTrackCodec{})
+	t.Cleanup(func() { delete(trackCodecs, "0.909-vel") })
+
+	if _, ok := resolveTrackCodec("0.909-vel-alpha").(VelocityTrackCodec); !ok {
+		t.Fatalf("resolveTrackCodec() did not pick the registered velocity codec")
+	}
+	if _, ok := resolveTrackCodec("0.808-alpha").(defaultTrackCodec); !ok {
+		t.Fatalf("resolveTrackCodec() did not fall back to the default codec")
+	}
+}
+
+func TestDefaultTrackCodecReadTrackDistinguishesCleanEOFFromTruncation(t *testing.T) {
+	if _, err := (defaultTrackCodec{}).ReadTrack(&TrackReader{bytes.NewReader(nil), nil}); err != io.EOF {
+		t.Fatalf("ReadTrack() on an empty stream error = %v, want io.EOF", err)
+	}
+
+	truncated := []byte{0x01, 0x00, 0x00, 0x00, 0x04} // id + instrument length, nothing after
+	_, err := (defaultTrackCodec{}).ReadTrack(&TrackReader{bytes.NewReader(truncated), nil})
+	if err != io.ErrUnexpectedEOF {
+		t.Fatalf("ReadTrack() on a truncated track error = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestDecodeReturnsErrUnexpectedEOFOnTruncatedTrailingTrack(t *testing.T) {
+	want := fixturePattern()
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(want); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	truncated := buf.Bytes()[:buf.Len()-3] // chop off part of the final track's steps
+
+	if _, err := NewDecoder(bytes.NewReader(truncated)).Decode(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("Decode() error = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
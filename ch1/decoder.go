@@ -34,12 +34,16 @@ func NewDecoder(r io.Reader) *Decoder {
 	return &Decoder{r: r}
 }
 
-type errReader struct {
+// TrackReader is a sticky-error binary reader shared by Decoder and every
+// registered TrackCodec. It is exported (it was the unexported errReader
+// until TrackCodec made it part of the package's public surface) so that
+// codecs defined outside this package can implement TrackCodec.ReadTrack.
+type TrackReader struct {
 	r   io.Reader
 	err error
 }
 
-func (r *errReader) Read(order binary.ByteOrder, data interface{}) error {
+func (r *TrackReader) Read(order binary.ByteOrder, data interface{}) error {
 	if r.err != nil {
 		return r.err
 	}
@@ -48,7 +52,7 @@ func (r *errReader) Read(order binary.ByteOrder, data interface{}) error {
 	return r.err
 }
 
-func (r *errReader) ReadFull(buf []byte) (n int, _ error) {
+func (r *TrackReader) ReadFull(buf []byte) (n int, _ error) {
 	if r.err != nil {
 		return 0, r.err
 	}
@@ -57,6 +61,11 @@ func (r *errReader) ReadFull(buf []byte) (n int, _ error) {
 	return n, r.err
 }
 
+// Err returns the first error encountered by Read or ReadFull, if any.
+func (r *TrackReader) Err() error {
+	return r.err
+}
+
 func (d *Decoder) Decode() (*Pattern, error) {
 	var (
 		header struct {
@@ -67,7 +76,7 @@ func (d *Decoder) Decode() (*Pattern, error) {
 		tempo float32
 	)
 
-	er := errReader{d.r, nil}
+	er := TrackReader{d.r, nil}
 	er.Read(binary.BigEndian, &header)
 	er.Read(binary.LittleEndian, &tempo)
 
@@ -80,7 +89,7 @@ func (d *Decoder) Decode() (*Pattern, error) {
 
 	version := strings.TrimRight(
 		string(header.Version[:]),
-		string(0), // trim zero-byte values
+		"\x00", // trim zero-byte values
 	)
 
 	// use limitreader limited by header size minus
@@ -93,39 +102,20 @@ func (d *Decoder) Decode() (*Pattern, error) {
 		Tracks:  []Track{},
 	}
 
-loop:
+	codec := resolveTrackCodec(version)
+
 	for {
-		var id uint32
-		err := er.Read(binary.LittleEndian, &id)
+		t, err := codec.ReadTrack(&er)
 
 		switch {
 		case err == io.EOF:
-			break loop // done reading
+			return p, nil // done reading
 		case err != nil:
 			return nil, err
 		}
 
-		var len byte
-		er.Read(binary.LittleEndian, &len)
-		instrument := make([]byte, len)
-		steps := make([]byte, 16)
-		er.ReadFull(instrument)
-		er.ReadFull(steps)
-
-		if er.err != nil {
-			return nil, er.err
-		}
-
-		t := Track{
-			ID:         id,
-			Instrument: string(instrument),
-			Steps:      steps,
-		}
-
 		p.Tracks = append(p.Tracks, t)
 	}
-
-	return p, nil
 }
 
 // Pattern is the high level representation of the
@@ -134,6 +124,11 @@ type Pattern struct {
 	Version string
 	Tempo   float32
 	Tracks  []Track
+
+	// section and index are set by NewSectionDecoder and back TrackAt;
+	// they are nil for patterns produced by Decode.
+	section io.ReaderAt
+	index   []trackSection
 }
 
 func (p Pattern) String() string {
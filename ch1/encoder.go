@@ -0,0 +1,82 @@
+package drum
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+)
+
+var (
+	InvalidVersion    = errors.New("version exceeds 32 bytes")
+	InvalidInstrument = errors.New("instrument name exceeds 255 bytes")
+	InvalidSteps      = errors.New("track must have exactly 16 steps")
+)
+
+// EncodeFile encodes the pattern and writes it to the provided path,
+// overwriting any file that is already there.
+func EncodeFile(pattern *Pattern, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return NewEncoder(file).Encode(pattern)
+}
+
+type Encoder struct {
+	w io.Writer
+}
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes pattern to the underlying writer in the .splice format,
+// the inverse of Decoder.Decode.
+func (e *Encoder) Encode(p *Pattern) error {
+	if len(p.Version) > 32 {
+		return InvalidVersion
+	}
+
+	for _, t := range p.Tracks {
+		if len(t.Instrument) > 255 {
+			return InvalidInstrument
+		}
+		if len(t.Steps) != 16 {
+			return InvalidSteps
+		}
+	}
+
+	var size int64 = 36 // version + tempo
+	for _, t := range p.Tracks {
+		size += 4 + 1 + int64(len(t.Instrument)) + 16
+	}
+
+	header := struct {
+		Splice  [6]byte
+		Size    int64
+		Version [32]byte
+	}{
+		Size: size,
+	}
+	copy(header.Splice[:], "SPLICE")
+	copy(header.Version[:], p.Version)
+
+	if err := binary.Write(e.w, binary.BigEndian, header); err != nil {
+		return err
+	}
+	if err := binary.Write(e.w, binary.LittleEndian, p.Tempo); err != nil {
+		return err
+	}
+
+	codec := resolveTrackCodec(p.Version)
+	for _, t := range p.Tracks {
+		if err := codec.WriteTrack(e.w, t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package drum
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"sort"
+)
+
+// InstrumentMap maps an instrument name, as found in Track.Instrument, to a
+// General MIDI drum note number.
+type InstrumentMap map[string]uint8
+
+// DefaultInstrumentMap covers the instrument names commonly found in
+// .splice fixtures; it is consulted for any instrument missing from the
+// InstrumentMap passed to WriteSMF.
+var DefaultInstrumentMap = InstrumentMap{
+	"kick":      36,
+	"snare":     38,
+	"clap":      39,
+	"closed-hh": 42,
+	"hihat":     42,
+	"open-hh":   46,
+	"low-tom":   41,
+	"mid-tom":   45,
+	"hi-tom":    50,
+	"crash":     49,
+	"ride":      51,
+}
+
+const (
+	ppq          = 480 // pulses (ticks) per quarter note
+	ticksPerStep = 120 // a step is a 16th note at 480 PPQ
+)
+
+type midiEvent struct {
+	tick     uint32
+	status   byte
+	note     byte
+	velocity byte
+}
+
+// WriteSMF writes p as a format 0 Standard MIDI File to w. Each track's
+// Instrument is looked up in mapping, falling back to DefaultInstrumentMap,
+// to decide which note number sounds a nonzero step; tracks whose
+// instrument is absent from both are skipped.
+func (p *Pattern) WriteSMF(w io.Writer, mapping InstrumentMap) error {
+	var events []midiEvent
+
+	for _, t := range p.Tracks {
+		note, ok := mapping[t.Instrument]
+		if !ok {
+			note, ok = DefaultInstrumentMap[t.Instrument]
+		}
+		if !ok {
+			continue
+		}
+
+		for step, v := range t.Steps {
+			if v == 0 {
+				continue
+			}
+
+			tick := uint32(step * ticksPerStep)
+			events = append(events,
+				midiEvent{tick: tick, status: 0x90, note: note, velocity: 64},
+				midiEvent{tick: tick + ticksPerStep, status: 0x80, note: note, velocity: 0},
+			)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].tick < events[j].tick })
+
+	var body bytes.Buffer
+
+	usPerQuarter := uint32(60000000 / float64(p.Tempo))
+	writeVarLength(&body, 0)
+	body.Write([]byte{0xFF, 0x51, 0x03, byte(usPerQuarter >> 16), byte(usPerQuarter >> 8), byte(usPerQuarter)})
+
+	var last uint32
+	for _, e := range events {
+		writeVarLength(&body, e.tick-last)
+		last = e.tick
+		body.Write([]byte{e.status, e.note, e.velocity})
+	}
+
+	writeVarLength(&body, 0)
+	body.Write([]byte{0xFF, 0x2F, 0x00})
+
+	header := make([]byte, 6)
+	binary.BigEndian.PutUint16(header[0:2], 0) // format 0
+	binary.BigEndian.PutUint16(header[2:4], 1) // ntrks
+	binary.BigEndian.PutUint16(header[4:6], ppq)
+
+	if err := writeSMFChunk(w, "MThd", header); err != nil {
+		return err
+	}
+	return writeSMFChunk(w, "MTrk", body.Bytes())
+}
+
+func writeSMFChunk(w io.Writer, id string, body []byte) error {
+	if _, err := io.WriteString(w, id); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, uint32(len(body))); err != nil {
+		return err
+	}
+	_, err := w.Write(body)
+	return err
+}
+
+// writeVarLength encodes v as a MIDI variable-length quantity: 7 bits per
+// byte, with the high bit set on every byte but the last.
+func writeVarLength(w *bytes.Buffer, v uint32) {
+	buf := []byte{byte(v & 0x7F)}
+	for v >>= 7; v > 0; v >>= 7 {
+		buf = append([]byte{byte(v&0x7F) | 0x80}, buf...)
+	}
+	w.Write(buf)
+}
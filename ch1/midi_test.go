@@ -0,0 +1,181 @@
+package drum
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+// smfFile is the minimal in-package parse of a format 0 Standard MIDI File
+// needed to verify WriteSMF's output round-trips; it is not a general SMF
+// reader.
+type smfFile struct {
+	format, ntrks, division uint16
+	usPerQuarter            uint32
+	events                  []midiEvent
+}
+
+func parseSMF(t *testing.T, r *bytes.Reader) smfFile {
+	t.Helper()
+
+	mthd := readSMFChunk(t, r, "MThd")
+	if len(mthd) != 6 {
+		t.Fatalf("MThd body length = %d, want 6", len(mthd))
+	}
+
+	f := smfFile{
+		format:   binary.BigEndian.Uint16(mthd[0:2]),
+		ntrks:    binary.BigEndian.Uint16(mthd[2:4]),
+		division: binary.BigEndian.Uint16(mthd[4:6]),
+	}
+
+	track := bufio.NewReader(bytes.NewReader(readSMFChunk(t, r, "MTrk")))
+
+	var tick uint32
+	for {
+		delta, err := readVarLength(track)
+		if err != nil {
+			t.Fatalf("readVarLength: %v", err)
+		}
+		tick += delta
+
+		status, err := track.ReadByte()
+		if err != nil {
+			t.Fatalf("ReadByte(status): %v", err)
+		}
+
+		if status == 0xFF {
+			metaType, _ := track.ReadByte()
+			length, err := readVarLength(track)
+			if err != nil {
+				t.Fatalf("readVarLength(meta length): %v", err)
+			}
+			data := make([]byte, length)
+			if _, err := io.ReadFull(track, data); err != nil {
+				t.Fatalf("read meta data: %v", err)
+			}
+
+			switch metaType {
+			case 0x51:
+				f.usPerQuarter = uint32(data[0])<<16 | uint32(data[1])<<8 | uint32(data[2])
+			case 0x2F:
+				return f // end of track
+			}
+			continue
+		}
+
+		note, _ := track.ReadByte()
+		velocity, _ := track.ReadByte()
+		f.events = append(f.events, midiEvent{tick: tick, status: status, note: note, velocity: velocity})
+	}
+}
+
+func readSMFChunk(t *testing.T, r *bytes.Reader, wantID string) []byte {
+	t.Helper()
+
+	id := make([]byte, 4)
+	if _, err := io.ReadFull(r, id); err != nil {
+		t.Fatalf("read chunk id: %v", err)
+	}
+	if string(id) != wantID {
+		t.Fatalf("chunk id = %q, want %q", id, wantID)
+	}
+
+	var size uint32
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+		t.Fatalf("read chunk size: %v", err)
+	}
+
+	body := make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil {
+		t.Fatalf("read chunk body: %v", err)
+	}
+	return body
+}
+
+func readVarLength(r *bufio.Reader) (uint32, error) {
+	var v uint32
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		v = v<<7 | uint32(b&0x7F)
+		if b&0x80 == 0 {
+			return v, nil
+		}
+	}
+}
+
+func TestWriteSMFRoundTrip(t *testing.T) {
+	p := &Pattern{
+		Tempo: 120,
+		Tracks: []Track{
+			{Instrument: "kick", Steps: Steps{1, 0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0}},
+			{Instrument: "snare", Steps: Steps{0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0}},
+			{Instrument: "unmapped-instrument", Steps: Steps{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := p.WriteSMF(&buf, InstrumentMap{}); err != nil {
+		t.Fatalf("WriteSMF: %v", err)
+	}
+
+	got := parseSMF(t, bytes.NewReader(buf.Bytes()))
+
+	if got.format != 0 {
+		t.Errorf("format = %d, want 0", got.format)
+	}
+	if got.ntrks != 1 {
+		t.Errorf("ntrks = %d, want 1", got.ntrks)
+	}
+	if got.division != ppq {
+		t.Errorf("division = %d, want %d", got.division, ppq)
+	}
+
+	wantUsPerQuarter := uint32(60000000 / 120)
+	if got.usPerQuarter != wantUsPerQuarter {
+		t.Errorf("usPerQuarter = %d, want %d", got.usPerQuarter, wantUsPerQuarter)
+	}
+
+	wantNotes := 4 + 2 // 4 kick hits, 2 snare hits; the unmapped track is skipped
+	wantEvents := wantNotes * 2
+	if len(got.events) != wantEvents {
+		t.Fatalf("len(events) = %d, want %d (%d note on/off pairs)", len(got.events), wantEvents, wantNotes)
+	}
+
+	first := got.events[0]
+	if first.status != 0x90 || first.note != DefaultInstrumentMap["kick"] || first.tick != 0 {
+		t.Errorf("first event = %+v, want note-on kick at tick 0", first)
+	}
+
+	firstOff := got.events[1]
+	if firstOff.status != 0x80 || firstOff.tick != ticksPerStep {
+		t.Errorf("second event = %+v, want note-off at tick %d", firstOff, ticksPerStep)
+	}
+}
+
+func TestWriteSMFUsesCallerMapping(t *testing.T) {
+	p := &Pattern{
+		Tempo: 120,
+		Tracks: []Track{
+			{Instrument: "808-kick", Steps: Steps{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := p.WriteSMF(&buf, InstrumentMap{"808-kick": 99}); err != nil {
+		t.Fatalf("WriteSMF: %v", err)
+	}
+
+	got := parseSMF(t, bytes.NewReader(buf.Bytes()))
+	if len(got.events) != 2 {
+		t.Fatalf("len(events) = %d, want 2", len(got.events))
+	}
+	if got.events[0].note != 99 {
+		t.Errorf("note = %d, want 99", got.events[0].note)
+	}
+}
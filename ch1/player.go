@@ -0,0 +1,311 @@
+package drum
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const sampleRate = 44100
+
+var (
+	ErrUnknownInstrument = errors.New("drum: no sample registered for instrument")
+	ErrNoOutputDevice    = errors.New("drum: no output device configured")
+	ErrNotPCM16          = errors.New("drum: wav sample is not 16-bit PCM")
+)
+
+// SampleBank supplies the PCM sample data for each instrument referenced by
+// a Pattern's tracks. Samples are expected to be mono or stereo 16-bit PCM
+// WAV files sampled at 44.1kHz.
+type SampleBank interface {
+	Sample(instrument string) (io.Reader, error)
+}
+
+// MapSampleBank is a SampleBank backed by an in-memory set of readers, keyed
+// by instrument name.
+type MapSampleBank map[string]io.Reader
+
+func (b MapSampleBank) Sample(instrument string) (io.Reader, error) {
+	r, ok := b[instrument]
+	if !ok {
+		return nil, ErrUnknownInstrument
+	}
+	return r, nil
+}
+
+// DirSampleBank loads samples lazily from a directory containing one
+// "<instrument>.wav" file per instrument name used by a Pattern.
+type DirSampleBank string
+
+func (b DirSampleBank) Sample(instrument string) (io.Reader, error) {
+	f, err := os.Open(filepath.Join(string(b), instrument+".wav"))
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// OpenOutputDevice opens the OS default audio output device as a
+// io.WriteCloser accepting a WAV stream. Platforms that want to support
+// Player.Play out of the box should replace this hook at init time; the
+// default returns ErrNoOutputDevice so the package stays usable headlessly.
+var OpenOutputDevice = func() (io.WriteCloser, error) {
+	return nil, ErrNoOutputDevice
+}
+
+// Player renders Patterns to PCM audio, reading instrument samples from
+// Bank.
+type Player struct {
+	Bank SampleBank
+}
+
+func NewPlayer(bank SampleBank) *Player {
+	return &Player{Bank: bank}
+}
+
+// Play renders p, looped `loops` times, and streams the result as a WAV
+// stream to the OS default output device.
+func (pl *Player) Play(p *Pattern, loops int) error {
+	out, err := OpenOutputDevice()
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	mix, err := renderMix(p, pl.Bank)
+	if err != nil {
+		return err
+	}
+
+	return writeWAV(out, loopMix(mix, loops), sampleRate)
+}
+
+// RenderWAV renders a single pass of p, mixing each step's active tracks
+// against samples loaded from bank, and writes the result as a mono 16-bit
+// WAV stream to w. It performs no device I/O, which makes it the entry
+// point for headless tests; Player.Play builds on it to support looping.
+func RenderWAV(p *Pattern, bank SampleBank, w io.Writer) error {
+	mix, err := renderMix(p, bank)
+	if err != nil {
+		return err
+	}
+
+	return writeWAV(w, mix, sampleRate)
+}
+
+// renderMix mixes one pass (16 steps) of p against samples loaded from
+// bank and returns the clipped mono samples.
+func renderMix(p *Pattern, bank SampleBank) ([]int16, error) {
+	samples, err := loadSamples(p, bank)
+	if err != nil {
+		return nil, err
+	}
+
+	stepDur := 60 / float64(p.Tempo) / 4
+	stepLen := int(stepDur * sampleRate)
+	mix := make([]int32, 16*stepLen)
+
+	for step := 0; step < 16; step++ {
+		offset := step * stepLen
+		for _, t := range p.Tracks {
+			if step >= len(t.Steps) || t.Steps[step] == 0 {
+				continue
+			}
+			mixInto(mix[offset:], samples[t.Instrument])
+		}
+	}
+
+	return clip(mix), nil
+}
+
+// loopMix concatenates mix `loops` times; since each pass renders
+// independently of the others, repeating the rendered samples is
+// equivalent to mixing the full looped pattern.
+func loopMix(mix []int16, loops int) []int16 {
+	if loops < 1 {
+		loops = 1
+	}
+
+	out := make([]int16, 0, len(mix)*loops)
+	for i := 0; i < loops; i++ {
+		out = append(out, mix...)
+	}
+	return out
+}
+
+func loadSamples(p *Pattern, bank SampleBank) (map[string][]int16, error) {
+	samples := make(map[string][]int16, len(p.Tracks))
+	for _, t := range p.Tracks {
+		if _, ok := samples[t.Instrument]; ok {
+			continue
+		}
+
+		r, err := bank.Sample(t.Instrument)
+		if err != nil {
+			return nil, err
+		}
+
+		pcm, err := readWAV(r)
+		if err != nil {
+			return nil, err
+		}
+		samples[t.Instrument] = pcm
+	}
+	return samples, nil
+}
+
+func mixInto(dst []int32, src []int16) {
+	if len(src) > len(dst) {
+		src = src[:len(dst)]
+	}
+	for i, s := range src {
+		dst[i] += int32(s)
+	}
+}
+
+func clip(mix []int32) []int16 {
+	out := make([]int16, len(mix))
+	for i, s := range mix {
+		switch {
+		case s > 32767:
+			out[i] = 32767
+		case s < -32768:
+			out[i] = -32768
+		default:
+			out[i] = int16(s)
+		}
+	}
+	return out
+}
+
+// readWAV parses a canonical PCM WAV file and returns its samples
+// downmixed to mono.
+func readWAV(r io.Reader) ([]int16, error) {
+	er := TrackReader{r, nil}
+
+	var riff struct {
+		ChunkID   [4]byte
+		ChunkSize uint32
+		Format    [4]byte
+	}
+	er.Read(binary.LittleEndian, &riff)
+	if er.err != nil {
+		return nil, er.err
+	}
+	if string(riff.ChunkID[:]) != "RIFF" || string(riff.Format[:]) != "WAVE" {
+		return nil, InvalidHeader
+	}
+
+	var (
+		channels      uint16
+		bitsPerSample uint16
+		pcm           []int16
+	)
+
+	for {
+		var id [4]byte
+		var size uint32
+		if err := er.Read(binary.LittleEndian, &id); err != nil {
+			break
+		}
+		er.Read(binary.LittleEndian, &size)
+		if er.err != nil {
+			break
+		}
+
+		switch string(id[:]) {
+		case "fmt ":
+			var fmtChunk struct {
+				AudioFormat   uint16
+				NumChannels   uint16
+				SampleRate    uint32
+				ByteRate      uint32
+				BlockAlign    uint16
+				BitsPerSample uint16
+			}
+			er.Read(binary.LittleEndian, &fmtChunk)
+			if er.err != nil {
+				return nil, er.err
+			}
+			channels = fmtChunk.NumChannels
+			bitsPerSample = fmtChunk.BitsPerSample
+			if pad := int64(size) - 16; pad > 0 {
+				io.CopyN(io.Discard, er.r, pad)
+			}
+		case "data":
+			if bitsPerSample != 16 {
+				return nil, ErrNotPCM16
+			}
+			buf := make([]byte, size)
+			if _, err := er.ReadFull(buf); err != nil {
+				return nil, err
+			}
+			raw := make([]int16, size/2)
+			for i := range raw {
+				raw[i] = int16(binary.LittleEndian.Uint16(buf[i*2:]))
+			}
+			pcm = downmix(raw, channels)
+		default:
+			io.CopyN(io.Discard, er.r, int64(size))
+		}
+	}
+
+	return pcm, nil
+}
+
+func downmix(samples []int16, channels uint16) []int16 {
+	if channels <= 1 {
+		return samples
+	}
+
+	mono := make([]int16, len(samples)/int(channels))
+	for i := range mono {
+		var sum int32
+		for c := 0; c < int(channels); c++ {
+			sum += int32(samples[i*int(channels)+c])
+		}
+		mono[i] = int16(sum / int32(channels))
+	}
+	return mono
+}
+
+// writeWAV writes samples as a mono 16-bit PCM WAV file to w.
+func writeWAV(w io.Writer, samples []int16, rate int) error {
+	dataSize := uint32(len(samples) * 2)
+	header := struct {
+		ChunkID       [4]byte
+		ChunkSize     uint32
+		Format        [4]byte
+		Subchunk1ID   [4]byte
+		Subchunk1Size uint32
+		AudioFormat   uint16
+		NumChannels   uint16
+		SampleRate    uint32
+		ByteRate      uint32
+		BlockAlign    uint16
+		BitsPerSample uint16
+		Subchunk2ID   [4]byte
+		Subchunk2Size uint32
+	}{
+		ChunkSize:     36 + dataSize,
+		Subchunk1Size: 16,
+		AudioFormat:   1,
+		NumChannels:   1,
+		SampleRate:    uint32(rate),
+		ByteRate:      uint32(rate * 2),
+		BlockAlign:    2,
+		BitsPerSample: 16,
+		Subchunk2Size: dataSize,
+	}
+	copy(header.ChunkID[:], "RIFF")
+	copy(header.Format[:], "WAVE")
+	copy(header.Subchunk1ID[:], "fmt ")
+	copy(header.Subchunk2ID[:], "data")
+
+	if err := binary.Write(w, binary.LittleEndian, header); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, samples)
+}
@@ -0,0 +1,141 @@
+package drum
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// wavSample builds a mono 16-bit WAV file, as a SampleBank entry would
+// supply, containing exactly samples.
+func wavSample(t *testing.T, samples []int16) io.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := writeWAV(&buf, samples, sampleRate); err != nil {
+		t.Fatalf("writeWAV: %v", err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func testPattern() *Pattern {
+	return &Pattern{
+		Tempo: 120,
+		Tracks: []Track{
+			{Instrument: "kick", Steps: Steps{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}},
+			{Instrument: "snare", Steps: Steps{0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}},
+		},
+	}
+}
+
+func TestRenderWAVMixesActiveSteps(t *testing.T) {
+	kick := []int16{1000, 2000, 3000}
+	snare := []int16{500, 600}
+
+	bank := MapSampleBank{
+		"kick":  wavSample(t, kick),
+		"snare": wavSample(t, snare),
+	}
+
+	var buf bytes.Buffer
+	if err := RenderWAV(testPattern(), bank, &buf); err != nil {
+		t.Fatalf("RenderWAV: %v", err)
+	}
+
+	got, err := readWAV(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("readWAV: %v", err)
+	}
+
+	tempo := 120.0
+	stepLen := int(60 / tempo / 4 * sampleRate)
+	if len(got) != 16*stepLen {
+		t.Fatalf("len(got) = %d, want %d", len(got), 16*stepLen)
+	}
+
+	if !reflect.DeepEqual(got[:len(kick)], kick) {
+		t.Fatalf("step 0 samples = %v, want %v", got[:len(kick)], kick)
+	}
+	for _, s := range got[len(kick):stepLen] {
+		if s != 0 {
+			t.Fatalf("expected silence after the kick sample ends, got %d", s)
+		}
+	}
+
+	snareOffset := 4 * stepLen
+	if !reflect.DeepEqual(got[snareOffset:snareOffset+len(snare)], snare) {
+		t.Fatalf("step 4 samples = %v, want %v", got[snareOffset:snareOffset+len(snare)], snare)
+	}
+}
+
+func TestRenderWAVClipsOverlappingSamples(t *testing.T) {
+	p := &Pattern{
+		Tempo: 120,
+		Tracks: []Track{
+			{Instrument: "a", Steps: Steps{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}},
+			{Instrument: "b", Steps: Steps{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}},
+		},
+	}
+	bank := MapSampleBank{
+		"a": wavSample(t, []int16{30000}),
+		"b": wavSample(t, []int16{30000}),
+	}
+
+	var buf bytes.Buffer
+	if err := RenderWAV(p, bank, &buf); err != nil {
+		t.Fatalf("RenderWAV: %v", err)
+	}
+
+	got, err := readWAV(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("readWAV: %v", err)
+	}
+	if got[0] != 32767 {
+		t.Fatalf("got[0] = %d, want clipped to 32767", got[0])
+	}
+}
+
+func TestPlayerPlayLoopsTheRenderedMix(t *testing.T) {
+	newBank := func() SampleBank {
+		return MapSampleBank{
+			"kick":  wavSample(t, []int16{1000}),
+			"snare": wavSample(t, []int16{500}),
+		}
+	}
+
+	var out bytes.Buffer
+	prevOpen := OpenOutputDevice
+	OpenOutputDevice = func() (io.WriteCloser, error) {
+		return nopWriteCloser{&out}, nil
+	}
+	t.Cleanup(func() { OpenOutputDevice = prevOpen })
+
+	const loops = 3
+	if err := NewPlayer(newBank()).Play(testPattern(), loops); err != nil {
+		t.Fatalf("Play: %v", err)
+	}
+
+	single, err := renderMix(testPattern(), newBank())
+	if err != nil {
+		t.Fatalf("renderMix: %v", err)
+	}
+
+	got, err := readWAV(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatalf("readWAV: %v", err)
+	}
+	if len(got) != len(single)*loops {
+		t.Fatalf("len(got) = %d, want %d (single pass %d x %d loops)", len(got), len(single)*loops, len(single), loops)
+	}
+	if !reflect.DeepEqual(got[:len(single)], single) {
+		t.Fatalf("first loop pass = %v, want %v", got[:len(single)], single)
+	}
+	if !reflect.DeepEqual(got[len(single):2*len(single)], single) {
+		t.Fatalf("second loop pass = %v, want %v", got[len(single):2*len(single)], single)
+	}
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,110 @@
+package drum
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var ErrNoSectionIndex = errors.New("drum: pattern was not created with NewSectionDecoder")
+
+// trackSection records where a single track record lives within the
+// underlying ReaderAt, so it can be decoded on demand without holding the
+// rest of the file in memory.
+type trackSection struct {
+	offset int64
+	length int64
+}
+
+// SectionDecoder parses a .splice header and indexes its tracks by offset,
+// deferring per-track decoding until TrackAt is called. This keeps memory
+// use independent of file size, analogous to how seek-based binary format
+// readers handle large payloads.
+type SectionDecoder struct {
+	r    io.ReaderAt
+	size int64
+}
+
+func NewSectionDecoder(r io.ReaderAt, size int64) *SectionDecoder {
+	return &SectionDecoder{r: r, size: size}
+}
+
+// Decode parses the header and builds the track index, returning a Pattern
+// whose Tracks field is left nil: populating it would mean decoding every
+// track up front, defeating the point of lazy loading. Use NumTracks and
+// TrackAt to discover and decode tracks on demand.
+func (d *SectionDecoder) Decode() (*Pattern, error) {
+	var header struct {
+		Splice  [6]byte
+		Size    int64
+		Version [32]byte
+	}
+
+	headerBuf := make([]byte, 46)
+	if _, err := d.r.ReadAt(headerBuf, 0); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(bytes.NewReader(headerBuf), binary.BigEndian, &header); err != nil {
+		return nil, err
+	}
+	if string(header.Splice[:]) != "SPLICE" {
+		return nil, InvalidHeader
+	}
+
+	tempoBuf := make([]byte, 4)
+	if _, err := d.r.ReadAt(tempoBuf, 46); err != nil {
+		return nil, err
+	}
+	var tempo float32
+	binary.Read(bytes.NewReader(tempoBuf), binary.LittleEndian, &tempo)
+
+	version := strings.TrimRight(string(header.Version[:]), "\x00")
+
+	end := int64(14) + header.Size // Splice(6) + Size(8) + header.Size
+	var index []trackSection
+
+	for offset := int64(50); offset < end; { // 6 + 8 + 32 + 4
+		idLen := make([]byte, 5)
+		if _, err := d.r.ReadAt(idLen, offset); err != nil {
+			return nil, err
+		}
+		instrumentLen := int64(idLen[4])
+		length := 4 + 1 + instrumentLen + 16
+
+		index = append(index, trackSection{offset: offset, length: length})
+		offset += length
+	}
+
+	return &Pattern{
+		Version: version,
+		Tempo:   tempo,
+		section: d.r,
+		index:   index,
+	}, nil
+}
+
+// NumTracks reports the number of tracks indexed by SectionDecoder. It
+// returns 0 for patterns produced by Decode, which carry no track index.
+func (p *Pattern) NumTracks() int {
+	return len(p.index)
+}
+
+// TrackAt seeks to and decodes the i-th track of a Pattern produced by
+// SectionDecoder. It returns ErrNoSectionIndex for patterns produced by
+// Decode, which do not carry a track index.
+func (p *Pattern) TrackAt(i int) (Track, error) {
+	if p.section == nil {
+		return Track{}, ErrNoSectionIndex
+	}
+	if i < 0 || i >= len(p.index) {
+		return Track{}, fmt.Errorf("drum: track index %d out of range", i)
+	}
+
+	sec := p.index[i]
+	er := TrackReader{io.NewSectionReader(p.section, sec.offset, sec.length), nil}
+
+	return resolveTrackCodec(p.Version).ReadTrack(&er)
+}
@@ -0,0 +1,75 @@
+package drum
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestSectionDecoderIndexesAndDecodesTracksLazily(t *testing.T) {
+	want := fixturePattern()
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(want); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	r := bytes.NewReader(buf.Bytes())
+	p, err := NewSectionDecoder(r, int64(buf.Len())).Decode()
+	if err != nil {
+		t.Fatalf("SectionDecoder.Decode: %v", err)
+	}
+
+	if p.Version != want.Version || p.Tempo != want.Tempo {
+		t.Fatalf("header mismatch: got %+v, want %+v", p, want)
+	}
+	if p.Tracks != nil {
+		t.Fatalf("Tracks = %v, want nil for a section-decoded pattern", p.Tracks)
+	}
+	if got := p.NumTracks(); got != len(want.Tracks) {
+		t.Fatalf("NumTracks() = %d, want %d", got, len(want.Tracks))
+	}
+
+	for i, wantTrack := range want.Tracks {
+		got, err := p.TrackAt(i)
+		if err != nil {
+			t.Fatalf("TrackAt(%d): %v", i, err)
+		}
+		if !reflect.DeepEqual(got, wantTrack) {
+			t.Fatalf("TrackAt(%d) = %+v, want %+v", i, got, wantTrack)
+		}
+	}
+}
+
+func TestTrackAtRejectsOutOfRangeIndex(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(fixturePattern()); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	r := bytes.NewReader(buf.Bytes())
+	p, err := NewSectionDecoder(r, int64(buf.Len())).Decode()
+	if err != nil {
+		t.Fatalf("SectionDecoder.Decode: %v", err)
+	}
+
+	if _, err := p.TrackAt(p.NumTracks()); err == nil {
+		t.Fatal("TrackAt() with an out-of-range index should return an error")
+	}
+}
+
+func TestTrackAtOnPlainlyDecodedPatternReturnsErrNoSectionIndex(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(fixturePattern()); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	p, err := NewDecoder(&buf).Decode()
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	if _, err := p.TrackAt(0); err != ErrNoSectionIndex {
+		t.Fatalf("TrackAt() error = %v, want ErrNoSectionIndex", err)
+	}
+}